@@ -0,0 +1,150 @@
+// Package big wraps math/big's Int behind a small value-typed API tailored
+// to factorlib's needs: the arithmetic the sieve and primality code actually
+// uses, without every call site juggling pointers and in-place mutation.
+package big
+
+import (
+	stdbig "math/big"
+	stdrand "math/rand"
+)
+
+// Int is an arbitrary-precision integer. The zero value represents 0;
+// construct other values with Int64 or FromString.
+type Int struct {
+	v *stdbig.Int
+}
+
+// Int64 returns the Int representation of n.
+func Int64(n int64) Int {
+	return Int{v: stdbig.NewInt(n)}
+}
+
+// FromString parses s in the given base (0 means infer from prefix, as in
+// math/big) and reports whether parsing succeeded.
+func FromString(s string, base int) (Int, bool) {
+	v, ok := new(stdbig.Int).SetString(s, base)
+	if !ok {
+		return Int{}, false
+	}
+	return Int{v: v}, true
+}
+
+func (x Int) std() *stdbig.Int {
+	if x.v == nil {
+		return stdbig.NewInt(0)
+	}
+	return x.v
+}
+
+// Int64 returns x as an int64, truncating if x does not fit.
+func (x Int) Int64() int64 {
+	return x.std().Int64()
+}
+
+// IsInt64 reports whether x fits in an int64.
+func (x Int) IsInt64() bool {
+	return x.std().IsInt64()
+}
+
+// Sign returns -1, 0 or +1 depending on whether x is negative, zero or
+// positive.
+func (x Int) Sign() int {
+	return x.std().Sign()
+}
+
+// Cmp compares x and y, returning -1, 0 or +1.
+func (x Int) Cmp(y Int) int {
+	return x.std().Cmp(y.std())
+}
+
+// Add returns x+y.
+func (x Int) Add(y Int) Int {
+	return Int{v: new(stdbig.Int).Add(x.std(), y.std())}
+}
+
+// Sub returns x-y.
+func (x Int) Sub(y Int) Int {
+	return Int{v: new(stdbig.Int).Sub(x.std(), y.std())}
+}
+
+// Mul returns x*y.
+func (x Int) Mul(y Int) Int {
+	return Int{v: new(stdbig.Int).Mul(x.std(), y.std())}
+}
+
+// Square returns x*x.
+func (x Int) Square() Int {
+	return x.Mul(x)
+}
+
+// Div returns the truncated quotient x/y.
+func (x Int) Div(y Int) Int {
+	return Int{v: new(stdbig.Int).Quo(x.std(), y.std())}
+}
+
+// Mod returns x mod n, always in [0, n).
+func (x Int) Mod(n Int) Int {
+	return Int{v: new(stdbig.Int).Mod(x.std(), n.std())}
+}
+
+// Mod64 returns x mod n, always in [0, n), as an int64. The result always
+// fits in an int64, since n itself is an int64.
+func (x Int) Mod64(n int64) int64 {
+	return new(stdbig.Int).Mod(x.std(), stdbig.NewInt(n)).Int64()
+}
+
+// Exp returns x^y mod m. If m is the zero Int, the result is the plain
+// (unreduced) power.
+func (x Int) Exp(y, m Int) Int {
+	var mv *stdbig.Int
+	if m.v != nil && m.Sign() != 0 {
+		mv = m.std()
+	}
+	return Int{v: new(stdbig.Int).Exp(x.std(), y.std(), mv)}
+}
+
+// ModInverse returns the multiplicative inverse of x mod n.
+func (x Int) ModInverse(n Int) Int {
+	return Int{v: new(stdbig.Int).ModInverse(x.std(), n.std())}
+}
+
+// GCD returns the greatest common divisor of x and y.
+func (x Int) GCD(y Int) Int {
+	return Int{v: new(stdbig.Int).GCD(nil, nil, x.std(), y.std())}
+}
+
+// Bit returns the value of the i'th bit of x.
+func (x Int) Bit(i int) uint {
+	return x.std().Bit(i)
+}
+
+// BitLen returns the length of the absolute value of x in bits.
+func (x Int) BitLen() int {
+	return x.std().BitLen()
+}
+
+// Neg returns -x.
+func (x Int) Neg() Int {
+	return Int{v: new(stdbig.Int).Neg(x.std())}
+}
+
+// Sqrt returns floor(sqrt(x)) for x >= 0.
+func Sqrt(x Int) Int {
+	return Int{v: new(stdbig.Int).Sqrt(x.std())}
+}
+
+// IsEven reports whether x is divisible by two.
+func (x Int) IsEven() bool {
+	return x.std().Bit(0) == 0
+}
+
+// Rand returns a uniform random Int in [0, x), using rnd as the source of
+// randomness.
+func (x Int) Rand(rnd *stdrand.Rand) Int {
+	return Int{v: new(stdbig.Int).Rand(rnd, x.std())}
+}
+
+// String returns the base-10 representation of x.
+func (x Int) String() string {
+	return x.std().String()
+}
@@ -0,0 +1,199 @@
+package math
+
+import (
+	"math/rand"
+
+	"github.com/cfschilham/factorlib/big"
+)
+
+// cipollaThreshold is the 2-adic valuation of p-1 above which
+// Tonelli-Shanks' inner loop degrades enough that the Cipolla fallback
+// below is used instead.
+const cipollaThreshold = 20
+
+// BigSqrtModP returns a square root of a mod the prime p, i.e. an x such
+// that x*x = a (mod p). a must be a quadratic residue mod p. This is the
+// big.Int-native counterpart of SqrtModP, for factor-base primes beyond
+// int64.
+func BigSqrtModP(a, p big.Int, rnd *rand.Rand) big.Int {
+	zero := big.Int64(0)
+	one := big.Int64(1)
+	two := big.Int64(2)
+	three := big.Int64(3)
+	four := big.Int64(4)
+	five := big.Int64(5)
+	eight := big.Int64(8)
+
+	a = a.Mod(p)
+	if a.Sign() == 0 {
+		return zero
+	}
+	if p.Cmp(two) == 0 {
+		return a
+	}
+
+	if p.Mod(four).Cmp(three) == 0 {
+		e := p.Add(one).Div(four)
+		return a.Exp(e, p)
+	}
+	if p.Mod(eight).Cmp(five) == 0 {
+		// Atkin's formula: b = (2a)^((p-5)/8), i = 2ab^2, x = ab(i-1).
+		twoA := two.Mul(a).Mod(p)
+		b := twoA.Exp(p.Sub(five).Div(eight), p)
+		i := twoA.Mul(b).Mul(b).Mod(p)
+		return a.Mul(b).Mul(i.Sub(one)).Mod(p)
+	}
+
+	// Q*2^s = p-1, Q odd.
+	q, s := p.Sub(one), uint(0)
+	for q.IsEven() {
+		q = q.Div(two)
+		s++
+	}
+
+	if s > cipollaThreshold {
+		return cipollaSqrt(a, p, rnd)
+	}
+
+	// Find a quadratic nonresidue z via the Jacobi symbol.
+	var z big.Int
+	for {
+		z = p.Rand(rnd)
+		if z.Sign() == 0 {
+			continue
+		}
+		if BigJacobi(z, p) == -1 {
+			break
+		}
+	}
+
+	m := s
+	c := z.Exp(q, p)
+	t := a.Exp(q, p)
+	r := a.Exp(q.Add(one).Div(two), p)
+
+	for t.Cmp(one) != 0 {
+		// Find least i, 0 < i < m, such that t^(2^i) = 1.
+		i := uint(0)
+		for tt := t; tt.Cmp(one) != 0; i++ {
+			tt = tt.Mul(tt).Mod(p)
+		}
+		exp := big.Int64(1)
+		for j := uint(0); j < m-i-1; j++ {
+			exp = exp.Mul(two)
+		}
+		b := c.Exp(exp, p)
+		m = i
+		c = b.Mul(b).Mod(p)
+		t = t.Mul(b).Mul(b).Mod(p)
+		r = r.Mul(b).Mod(p)
+	}
+	return r
+}
+
+// fp2 is an element x + y*w of Fp[w]/(w^2 - d), the quadratic extension of
+// Fp used by Cipolla's algorithm.
+type fp2 struct {
+	x, y, d, p big.Int
+}
+
+func (a fp2) mul(b fp2) fp2 {
+	x := a.x.Mul(b.x).Add(a.y.Mul(b.y).Mul(a.d)).Mod(a.p)
+	y := a.x.Mul(b.y).Add(a.y.Mul(b.x)).Mod(a.p)
+	return fp2{x: x, y: y, d: a.d, p: a.p}
+}
+
+// pow raises a to the given exponent in Fp[w]/(w^2 - d).
+func (a fp2) pow(e big.Int) fp2 {
+	zero, one := big.Int64(0), big.Int64(1)
+	result := fp2{x: one, y: zero, d: a.d, p: a.p}
+	base := a
+	for e.Sign() > 0 {
+		if !e.IsEven() {
+			result = result.mul(base)
+		}
+		base = base.mul(base)
+		e = e.Div(big.Int64(2))
+	}
+	return result
+}
+
+// cipollaSqrt computes a square root of a mod prime p using Cipolla's
+// algorithm, which stays fast even when p-1 has a large power of two
+// factor (the case Tonelli-Shanks degrades on).
+func cipollaSqrt(a, p big.Int, rnd *rand.Rand) big.Int {
+	one := big.Int64(1)
+	var t, d big.Int
+	for {
+		t = p.Rand(rnd)
+		d = t.Mul(t).Sub(a).Mod(p)
+		if BigJacobi(d, p) == -1 {
+			break
+		}
+	}
+
+	base := fp2{x: t, y: one, d: d, p: p}
+	e := p.Add(one).Div(big.Int64(2))
+	r := base.pow(e)
+	return r.x
+}
+
+// BigSqrtModPK returns a square root of a mod p^k, for prime p, via Hensel
+// lifting from a square root mod p found with BigSqrtModP.
+func BigSqrtModPK(a, p big.Int, k uint, rnd *rand.Rand) big.Int {
+	pk := p.Exp(big.Int64(int64(k)), big.Int{})
+	a = a.Mod(pk)
+
+	if p.Cmp(big.Int64(2)) == 0 {
+		// Hensel lifting needs 2x invertible, which never holds mod a
+		// power of two.
+		for x := big.Int64(0); x.Cmp(pk) < 0; x = x.Add(big.Int64(1)) {
+			if x.Square().Mod(pk).Cmp(a) == 0 {
+				return x
+			}
+		}
+		return big.Int64(0)
+	}
+
+	r := BigSqrtModP(a.Mod(p), p, rnd)
+	cur := p
+	two := big.Int64(2)
+	for e := uint(1); e < k; e++ {
+		next := cur.Mul(p)
+		num := r.Mul(r).Sub(a.Mod(next)).Mod(next)
+		inv := two.Mul(r).Mod(next).ModInverse(next)
+		r = r.Sub(num.Mul(inv).Mod(next)).Mod(next)
+		cur = next
+	}
+	return r
+}
+
+// BigSqrtModN returns a square root of a mod n, where n's factorization
+// into prime powers is given by pps, by computing roots mod each prime
+// power with BigSqrtModPK and recombining them with CRT, all in big.Int
+// arithmetic so the result is correct even when n itself overflows int64.
+func BigSqrtModN(a big.Int, pps []PrimePower, rnd *rand.Rand) big.Int {
+	n := big.Int64(1)
+	for _, pp := range pps {
+		bigP := big.Int64(pp.P)
+		n = n.Mul(bigP.Exp(big.Int64(int64(pp.K)), big.Int{}))
+	}
+
+	x, mod := big.Int64(0), big.Int64(1)
+	for _, pp := range pps {
+		bigP := big.Int64(pp.P)
+		bigPk := bigP.Exp(big.Int64(int64(pp.K)), big.Int{})
+		if bigPk.Cmp(big.Int64(1)) == 0 {
+			continue
+		}
+		r := BigSqrtModPK(a, bigP, pp.K, rnd)
+
+		// CRT-combine (x, mod) with (r, pk).
+		m1inv := mod.Mod(bigPk).ModInverse(bigPk)
+		t := r.Sub(x).Mod(bigPk).Mul(m1inv).Mod(bigPk)
+		x = x.Add(mod.Mul(t))
+		mod = mod.Mul(bigPk)
+		x = x.Mod(n)
+	}
+	return x
+}
@@ -0,0 +1,80 @@
+package math
+
+import (
+	"github.com/cfschilham/factorlib/big"
+)
+
+// Jacobi returns the Jacobi symbol (a/n), which is +1, -1 or 0. n must be
+// odd; Jacobi panics otherwise. Unlike QuadraticResidue via Euler's
+// criterion, this needs no modular exponentiation, which makes it the
+// cheaper test to run before committing to SqrtModP/SqrtModPK - useful as a
+// factor-base filter and as the Selfridge-parameter search in a Lucas
+// probable-prime test.
+func Jacobi(a, n int64) int {
+	if n%2 == 0 {
+		panic("math: Jacobi called with even n")
+	}
+	if n < 0 {
+		panic("math: Jacobi called with negative n")
+	}
+
+	a %= n
+	if a < 0 {
+		a += n
+	}
+
+	result := 1
+	for a != 0 {
+		for a%2 == 0 {
+			a /= 2
+			if r := n % 8; r == 3 || r == 5 {
+				result = -result
+			}
+		}
+		a, n = n, a
+		if a%4 == 3 && n%4 == 3 {
+			result = -result
+		}
+		a %= n
+	}
+	if n == 1 {
+		return result
+	}
+	return 0
+}
+
+// BigJacobi is the big.Int-native counterpart of Jacobi, for moduli beyond
+// int64.
+func BigJacobi(a, n big.Int) int {
+	if n.Sign() < 0 {
+		panic("math: BigJacobi called with negative n")
+	}
+	two := big.Int64(2)
+	if n.Mod(two).Sign() == 0 {
+		panic("math: BigJacobi called with even n")
+	}
+
+	a = a.Mod(n)
+	one := big.Int64(1)
+	four := big.Int64(4)
+	eight := big.Int64(8)
+
+	result := 1
+	for a.Sign() != 0 {
+		for a.Mod(two).Sign() == 0 {
+			a = a.Div(two)
+			if r := n.Mod(eight).Int64(); r == 3 || r == 5 {
+				result = -result
+			}
+		}
+		a, n = n, a
+		if a.Mod(four).Int64() == 3 && n.Mod(four).Int64() == 3 {
+			result = -result
+		}
+		a = a.Mod(n)
+	}
+	if n.Cmp(one) == 0 {
+		return result
+	}
+	return 0
+}
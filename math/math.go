@@ -0,0 +1,244 @@
+// Package math collects the number-theoretic primitives the factoring
+// pipeline is built on: modular arithmetic, quadratic residues and modular
+// square roots.
+package math
+
+import (
+	"math/rand"
+)
+
+// PrimePower represents a prime p raised to exponent K, one factor of a
+// modulus's prime factorization.
+type PrimePower struct {
+	P int64
+	K uint
+}
+
+// GCD returns the greatest common divisor of a and b.
+func GCD(a, b int64) int64 {
+	if a < 0 {
+		a = -a
+	}
+	if b < 0 {
+		b = -b
+	}
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// Exp returns base^e.
+func Exp(base int64, e uint) int64 {
+	r := int64(1)
+	for ; e > 0; e-- {
+		r *= base
+	}
+	return r
+}
+
+// ModInv returns the multiplicative inverse of a mod n, assuming
+// GCD(a, n) == 1.
+func ModInv(a, n int64) int64 {
+	old_r, r := a, n
+	old_s, s := int64(1), int64(0)
+	for r != 0 {
+		q := old_r / r
+		old_r, r = r, old_r-q*r
+		old_s, s = s, old_s-q*s
+	}
+	return ((old_s % n) + n) % n
+}
+
+// QuadraticResidue reports whether a is a quadratic residue mod the prime
+// p. p must be prime; for prime p the Jacobi symbol coincides with the
+// Legendre symbol, so this is just Jacobi without the cost of Euler's
+// criterion's modular exponentiation.
+func QuadraticResidue(a, p int64) bool {
+	a %= p
+	if a < 0 {
+		a += p
+	}
+	if p == 2 {
+		// Jacobi requires an odd modulus; mod 2 every residue is a square.
+		return true
+	}
+	if a == 0 {
+		return true
+	}
+	return Jacobi(a, p) == 1
+}
+
+// SqrtModP returns a square root of a mod the prime p, i.e. an x such that
+// x*x = a (mod p). a must be a quadratic residue mod p. rnd is used to find
+// quadratic nonresidues during the Tonelli-Shanks search.
+func SqrtModP(a, p int64, rnd *rand.Rand) int64 {
+	a %= p
+	if a < 0 {
+		a += p
+	}
+	if a == 0 {
+		return 0
+	}
+	if p == 2 {
+		return a
+	}
+
+	// Q*2^s = p-1, Q odd.
+	q, s := p-1, uint(0)
+	for q%2 == 0 {
+		q /= 2
+		s++
+	}
+
+	if s == 1 {
+		// p = 3 (mod 4): x = a^((p+1)/4).
+		return powMod(a, (p+1)/4, p)
+	}
+
+	// Find a quadratic nonresidue z.
+	var z int64
+	for {
+		z = rnd.Int63n(p-2) + 2
+		if !QuadraticResidue(z, p) {
+			break
+		}
+	}
+
+	m := s
+	c := powMod(z, q, p)
+	t := powMod(a, q, p)
+	r := powMod(a, (q+1)/2, p)
+
+	for t != 1 {
+		// Find least i, 0 < i < m, such that t^(2^i) = 1.
+		i := uint(0)
+		for tt := t; tt != 1; i++ {
+			tt = tt * tt % p
+		}
+		b := powMod(c, Exp(2, m-i-1), p)
+		m = i
+		c = b * b % p
+		t = t * b % p * b % p
+		r = r * b % p
+	}
+	return r
+}
+
+// SqrtModPK returns a square root of a mod p^k, for prime p, via Hensel
+// lifting from a square root mod p found with SqrtModP.
+func SqrtModPK(a, p int64, k uint, rnd *rand.Rand) int64 {
+	pk := Exp(p, k)
+	a %= pk
+	if a < 0 {
+		a += pk
+	}
+
+	if p == 2 {
+		// Hensel lifting needs 2x invertible, which never holds mod a
+		// power of two; pk is small enough in practice to search directly.
+		for x := int64(0); x < pk; x++ {
+			if x*x%pk == a {
+				return x
+			}
+		}
+		return 0
+	}
+
+	r := SqrtModP(a%p, p, rnd)
+	cur := p
+	for e := uint(1); e < k; e++ {
+		next := cur * p
+		// Lift r (a root mod cur) to a root mod next.
+		// r' = r - (r*r - a) * inv(2*r) (mod next)
+		num := (r*r - a%next + next) % next
+		inv := ModInv((2*r)%next, next)
+		r = ((r-num*inv%next)%next + next) % next
+		cur = next
+	}
+	return r
+}
+
+// SqrtModN returns a square root of a mod n, where n's factorization into
+// prime powers is given by pps, by computing roots mod each prime power and
+// recombining them with CRT.
+func SqrtModN(a int64, pps []PrimePower, rnd *rand.Rand) int64 {
+	n := int64(1)
+	for _, pp := range pps {
+		n *= Exp(pp.P, pp.K)
+	}
+
+	x, mod := int64(0), int64(1)
+	for _, pp := range pps {
+		pk := Exp(pp.P, pp.K)
+		if pk == 1 {
+			continue
+		}
+		r := SqrtModPK(a%pk, pp.P, pp.K, rnd)
+
+		// CRT-combine (x, mod) with (r, pk).
+		m1inv := ModInv(mod%pk, pk)
+		t := ((r-x)%pk + pk) % pk * m1inv % pk
+		x = x + mod*t
+		mod *= pk
+		x = ((x % n) + n) % n
+	}
+	return x
+}
+
+// QuadraticModP returns all roots in Fp of ax^2 + bx + c, for prime p and
+// a != 0 (mod p).
+func QuadraticModP(a, b, c, p int64, rnd *rand.Rand) []int64 {
+	a %= p
+	b %= p
+	c %= p
+	if a == 0 {
+		panic("math: QuadraticModP called with a == 0 (mod p)")
+	}
+
+	if p == 2 {
+		var roots []int64
+		for x := int64(0); x < 2; x++ {
+			if (a*x*x+b*x+c)%2 == 0 {
+				roots = append(roots, x)
+			}
+		}
+		return roots
+	}
+
+	disc := ((b*b-4*a*c)%p + p) % p
+	if disc != 0 && !QuadraticResidue(disc, p) {
+		return nil
+	}
+
+	inv2a := ModInv((2*a)%p, p)
+	if disc == 0 {
+		x := ((-b*inv2a)%p + p) % p
+		return []int64{x}
+	}
+
+	sq := SqrtModP(disc, p, rnd)
+	x1 := (((-b+sq)%p + p) % p) * inv2a % p
+	x2 := (((-b-sq)%p + p) % p) * inv2a % p
+	if x1 == x2 {
+		return []int64{x1}
+	}
+	return []int64{x1, x2}
+}
+
+// powMod returns base^e mod m.
+func powMod(base, e, m int64) int64 {
+	base %= m
+	if base < 0 {
+		base += m
+	}
+	r := int64(1)
+	for e > 0 {
+		if e&1 == 1 {
+			r = r * base % m
+		}
+		base = base * base % m
+		e >>= 1
+	}
+	return r
+}
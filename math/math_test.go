@@ -63,6 +63,71 @@ func TestQR(t *testing.T) {
 	}
 }
 
+func TestJacobi(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		p := primes.Get(i)
+		if p == 2 {
+			continue
+		}
+
+		// find all quadratic residues mod p
+		m := map[int64]struct{}{}
+		for a := int64(0); a < p; a++ {
+			m[a*a%p] = struct{}{}
+		}
+
+		for a := int64(-p); a < p; a++ {
+			want := -1
+			if ((a%p)+p)%p == 0 {
+				want = 0
+			} else if _, isQR := m[((a%p)+p)%p]; isQR {
+				want = 1
+			}
+			if got := Jacobi(a, p); got != want {
+				t.Errorf("Jacobi(%d,%d)=%d, want %d", a, p, got, want)
+			}
+		}
+	}
+
+	// Composite odd moduli: cross-check against the multiplicativity of
+	// the Jacobi symbol over its prime factorization.
+	for n := int64(3); n < 200; n += 2 {
+		var factors []int64
+		x := n
+		for i := 0; x > 1; i++ {
+			p := primes.Get(i)
+			for x%p == 0 {
+				factors = append(factors, p)
+				x /= p
+			}
+		}
+		for a := int64(0); a < n; a++ {
+			want := 1
+			for _, p := range factors {
+				want *= Jacobi(a, p)
+			}
+			if got := Jacobi(a, n); got != want {
+				t.Errorf("Jacobi(%d,%d)=%d, want %d", a, n, got, want)
+			}
+		}
+	}
+}
+
+func TestBigJacobi(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		p := primes.Get(i)
+		if p == 2 {
+			continue
+		}
+		for a := int64(-p); a < p; a++ {
+			want := Jacobi(a, p)
+			if got := BigJacobi(big.Int64(a), big.Int64(p)); got != want {
+				t.Errorf("BigJacobi(%d,%d)=%d, want %d", a, p, got, want)
+			}
+		}
+	}
+}
+
 func TestSqrtModP(t *testing.T) {
 	rnd := rand.New(rand.NewSource(123))
 	for i := 0; i < 1000; i++ {
@@ -130,6 +195,73 @@ func TestSqrtModPK(t *testing.T) {
 	}
 }
 
+func TestBigSqrtModP(t *testing.T) {
+	rnd := rand.New(rand.NewSource(123))
+	for i := 0; i < 300; i++ {
+		p := primes.Get(i)
+
+		// compute roots mod p
+		m := map[int64]int64{}
+		for a := int64(0); a < p; a++ {
+			m[a*a%p] = a
+		}
+
+		for a := int64(0); a < p; a++ {
+			s, ok := m[a]
+			if !ok {
+				// a is a quadratic nonresidue
+				continue
+			}
+			r := BigSqrtModP(big.Int64(a), big.Int64(p), rnd).Int64()
+			if r != s && r != p-s {
+				t.Errorf("p=%d a=%d want %d or %d, got %d", p, a, s, p-s, r)
+			}
+		}
+	}
+}
+
+func TestBigSqrtModPK(t *testing.T) {
+	rnd := rand.New(rand.NewSource(123))
+	for i := 0; i < 300; i++ {
+		p := primes.Get(i)
+		for k := uint(1); ; k++ {
+			pk := Exp(p, k)
+			if pk > 10000 {
+				break
+			}
+
+			// compute roots mod p^k
+			m := map[int64][]int64{}
+			for a := int64(0); a < pk; a++ {
+				m[a*a%pk] = append(m[a*a%pk], a)
+			}
+
+			for a := int64(0); a < pk; a++ {
+				if a != 0 && GCD(a, pk) != 1 {
+					// a is not relatively prime to p^k
+					continue
+				}
+				s, ok := m[a]
+				if !ok {
+					// a is a quadratic nonresidue
+					continue
+				}
+				r := BigSqrtModPK(big.Int64(a), big.Int64(p), k, rnd).Int64()
+				ok = false
+				for _, x := range s {
+					if x == r {
+						ok = true
+						break
+					}
+				}
+				if !ok {
+					t.Errorf("pk=%d a=%d want element of %#v, got %d", pk, a, s, r)
+				}
+			}
+		}
+	}
+}
+
 func TestSqrtModN(t *testing.T) {
 	rnd := rand.New(rand.NewSource(123))
 	// test square roots mod 5^i 7^j 11^k for all quadratic residues mod those numbers.
@@ -210,6 +342,52 @@ func TestBigSqrtModN(t *testing.T) {
 	}
 }
 
+func TestBigSqrtModPCipollaFallback(t *testing.T) {
+	rnd := rand.New(rand.NewSource(123))
+	// p-1 = 5 * 2^25, so its 2-adic valuation is 25 - well above
+	// cipollaThreshold - which forces BigSqrtModP down the Cipolla path
+	// instead of Tonelli-Shanks.
+	const p = 167772161
+	bigP := big.Int64(p)
+
+	for _, x := range []int64{2, 3, 5, 7, 11, 123, 99999} {
+		a := big.Int64(x * x % p)
+		r := BigSqrtModP(a, bigP, rnd)
+		if got := r.Square().Mod(bigP); got.Cmp(a) != 0 {
+			t.Errorf("BigSqrtModP(%d,%d)=%d, want square root of %d (got x^2 mod p = %d)", a, p, r, a, got)
+		}
+	}
+}
+
+func TestCipollaSqrt(t *testing.T) {
+	rnd := rand.New(rand.NewSource(123))
+	const p = 167772161
+	bigP := big.Int64(p)
+
+	for _, x := range []int64{2, 3, 5, 7, 11, 123, 99999} {
+		a := big.Int64(x * x % p)
+		r := cipollaSqrt(a, bigP, rnd)
+		if got := r.Square().Mod(bigP); got.Cmp(a) != 0 {
+			t.Errorf("cipollaSqrt(%d,%d)=%d, want square root of %d (got x^2 mod p = %d)", a, p, r, a, got)
+		}
+	}
+}
+
+func TestBigSqrtModNOverflowsInt64(t *testing.T) {
+	rnd := rand.New(rand.NewSource(123))
+	// p^2 here is about 1.6e19, well past the int64 range (~9.2e18) that
+	// BigSqrtModN is supposed to be safe beyond.
+	const p = 3999999979
+	pps := []PrimePower{{p, 2}}
+	n := big.Int64(p).Exp(big.Int64(2), big.Int{})
+
+	a := big.Int64(4)
+	x := BigSqrtModN(a, pps, rnd)
+	if got := x.Square().Mod(n); got.Cmp(a) != 0 {
+		t.Errorf("bad bigSqrtModN a=%d n=%s x=%s, x^2 mod n = %s", a, n, x, got)
+	}
+}
+
 func TestQuadraticModP(t *testing.T) {
 	rnd := rand.New(rand.NewSource(123))
 	for i := 0; i < 25; i++ {
@@ -243,3 +421,55 @@ func TestQuadraticModP(t *testing.T) {
 		}
 	}
 }
+
+func TestRootsModPCubicQuartic(t *testing.T) {
+	rnd := rand.New(rand.NewSource(123))
+	for i := 0; i < 25; i++ {
+		p := primes.Get(i)
+		for _, degree := range []int{3, 4} {
+			for trial := 0; trial < 20; trial++ {
+				coeffs := make([]int64, degree+1)
+				for {
+					for j := range coeffs {
+						coeffs[j] = rnd.Int63n(p)
+					}
+					if coeffs[degree] != 0 {
+						break
+					}
+				}
+
+				roots := RootsModP(coeffs, p, rnd)
+
+				seen := map[int64]bool{}
+				for _, x := range roots {
+					if seen[x] {
+						t.Errorf("degree=%d coeffs=%v p=%d: returned root %d twice", degree, coeffs, p, x)
+					}
+					seen[x] = true
+
+					v := int64(0)
+					for j := len(coeffs) - 1; j >= 0; j-- {
+						v = (v*x + coeffs[j]) % p
+					}
+					if ((v%p)+p)%p != 0 {
+						t.Errorf("degree=%d coeffs=%v p=%d: x=%d is not a root (value %d)", degree, coeffs, p, x, v)
+					}
+				}
+
+				cnt := 0
+				for x := int64(0); x < p; x++ {
+					v := int64(0)
+					for j := len(coeffs) - 1; j >= 0; j-- {
+						v = (v*x + coeffs[j]) % p
+					}
+					if ((v%p)+p)%p == 0 {
+						cnt++
+					}
+				}
+				if cnt != len(roots) {
+					t.Errorf("degree=%d coeffs=%v p=%d: expected %d roots, got %d (%v)", degree, coeffs, p, cnt, len(roots), roots)
+				}
+			}
+		}
+	}
+}
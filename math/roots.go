@@ -0,0 +1,212 @@
+package math
+
+import "math/rand"
+
+// RootsModP returns all roots in Fp of the polynomial with the given
+// coefficients (coeffs[i] is the coefficient of x^i), for prime p. Roots
+// are returned without multiplicity and in no particular order.
+//
+// Quadratics are solved directly via QuadraticModP. Higher degrees use
+// Cantor-Zassenhaus: first g = gcd(f, x^p - x) isolates the product of
+// f's distinct linear factors, then equal-degree factorization splits g
+// into its roots.
+func RootsModP(coeffs []int64, p int64, rnd *rand.Rand) []int64 {
+	f := polyTrim(polyModCoeffs(coeffs, p), p)
+	d := polyDeg(f)
+	if d <= 0 {
+		return nil
+	}
+	if d == 1 {
+		return []int64{linearRoot(f, p)}
+	}
+	if d == 2 {
+		return QuadraticModP(f[2], f[1], f[0], p, rnd)
+	}
+
+	if p == 2 {
+		var roots []int64
+		for x := int64(0); x < 2; x++ {
+			if polyEval(f, x, p) == 0 {
+				roots = append(roots, x)
+			}
+		}
+		return roots
+	}
+
+	xPowPMinusX := polySub(polyPowMod([]int64{0, 1}, p, f, p), []int64{0, 1}, p)
+	g := polyGCD(f, xPowPMinusX, p)
+	g = polyTrim(g, p)
+	if polyDeg(g) <= 0 {
+		return nil
+	}
+	return equalDegreeSplit(g, p, rnd)
+}
+
+// equalDegreeSplit returns the roots of f, a polynomial known to be a
+// product of distinct linear factors over Fp, p an odd prime.
+func equalDegreeSplit(f []int64, p int64, rnd *rand.Rand) []int64 {
+	f = polyTrim(f, p)
+	d := polyDeg(f)
+	if d <= 0 {
+		return nil
+	}
+	if d == 1 {
+		return []int64{linearRoot(f, p)}
+	}
+
+	for {
+		alpha := rnd.Int63n(p)
+		h := polyPowMod([]int64{alpha, 1}, (p-1)/2, f, p)
+		h = polySub(h, []int64{1}, p)
+		g := polyGCD(h, f, p)
+		g = polyTrim(g, p)
+		gd := polyDeg(g)
+		if gd <= 0 || gd >= d {
+			continue
+		}
+		q, _ := polyDivMod(f, g, p)
+		return append(equalDegreeSplit(g, p, rnd), equalDegreeSplit(q, p, rnd)...)
+	}
+}
+
+// linearRoot returns the root of the linear polynomial f = f[0] + f[1]*x.
+func linearRoot(f []int64, p int64) int64 {
+	inv := ModInv(f[1], p)
+	return ((-f[0]*inv)%p + p) % p
+}
+
+// polyEval evaluates f at x mod p.
+func polyEval(f []int64, x, p int64) int64 {
+	r := int64(0)
+	for i := len(f) - 1; i >= 0; i-- {
+		r = (r*x + f[i]) % p
+	}
+	return ((r % p) + p) % p
+}
+
+// polyModCoeffs reduces every coefficient of f mod p.
+func polyModCoeffs(f []int64, p int64) []int64 {
+	r := make([]int64, len(f))
+	for i, c := range f {
+		r[i] = ((c % p) + p) % p
+	}
+	return r
+}
+
+// polyTrim drops high-degree zero coefficients.
+func polyTrim(f []int64, p int64) []int64 {
+	n := len(f)
+	for n > 0 && f[n-1]%p == 0 {
+		n--
+	}
+	return f[:n]
+}
+
+// polyDeg returns the degree of f, or -1 for the zero polynomial.
+func polyDeg(f []int64) int {
+	return len(f) - 1
+}
+
+// polySub returns f-g mod p.
+func polySub(f, g []int64, p int64) []int64 {
+	n := len(f)
+	if len(g) > n {
+		n = len(g)
+	}
+	r := make([]int64, n)
+	for i := 0; i < n; i++ {
+		var a, b int64
+		if i < len(f) {
+			a = f[i]
+		}
+		if i < len(g) {
+			b = g[i]
+		}
+		r[i] = ((a-b)%p + p) % p
+	}
+	return polyTrim(r, p)
+}
+
+// polyMulMod returns f*g mod p.
+func polyMulMod(f, g []int64, p int64) []int64 {
+	if len(f) == 0 || len(g) == 0 {
+		return nil
+	}
+	r := make([]int64, len(f)+len(g)-1)
+	for i, a := range f {
+		if a == 0 {
+			continue
+		}
+		for j, b := range g {
+			r[i+j] = (r[i+j] + a*b) % p
+		}
+	}
+	return polyTrim(r, p)
+}
+
+// polyDivMod returns the quotient and remainder of f divided by g mod p.
+func polyDivMod(f, g []int64, p int64) (q, r []int64) {
+	g = polyTrim(g, p)
+	r = append([]int64{}, f...)
+	r = polyTrim(r, p)
+	gd := polyDeg(g)
+	gLeadInv := ModInv(g[gd], p)
+
+	q = make([]int64, 0)
+	for polyDeg(r) >= gd {
+		rd := polyDeg(r)
+		coeff := r[rd] * gLeadInv % p
+		shift := rd - gd
+		for len(q) <= shift {
+			q = append(q, 0)
+		}
+		q[shift] = coeff
+
+		term := make([]int64, shift+gd+1)
+		for i, c := range g {
+			term[i+shift] = c * coeff % p
+		}
+		r = polySub(r, term, p)
+	}
+	return q, r
+}
+
+// polyModReduce reduces f mod g.
+func polyModReduce(f, g []int64, p int64) []int64 {
+	_, r := polyDivMod(f, g, p)
+	return r
+}
+
+// polyPowMod returns base^e mod g, mod p.
+func polyPowMod(base []int64, e int64, g []int64, p int64) []int64 {
+	result := []int64{1}
+	b := polyModReduce(base, g, p)
+	for e > 0 {
+		if e&1 == 1 {
+			result = polyModReduce(polyMulMod(result, b, p), g, p)
+		}
+		b = polyModReduce(polyMulMod(b, b, p), g, p)
+		e >>= 1
+	}
+	return result
+}
+
+// polyGCD returns gcd(f, g) mod p, via the Euclidean algorithm.
+func polyGCD(f, g []int64, p int64) []int64 {
+	f = polyTrim(f, p)
+	g = polyTrim(g, p)
+	for len(g) > 0 {
+		_, r := polyDivMod(f, g, p)
+		f, g = g, polyTrim(r, p)
+	}
+	if len(f) == 0 {
+		return f
+	}
+	// Normalize to monic.
+	inv := ModInv(f[len(f)-1], p)
+	r := make([]int64, len(f))
+	for i, c := range f {
+		r[i] = c * inv % p
+	}
+	return r
+}
@@ -0,0 +1,38 @@
+// Package primes provides prime generation and primality testing used to
+// build the factor bases that the sieve and trial-division stages rely on.
+package primes
+
+// table holds primes in ascending order, generated lazily and extended as
+// Get is asked for higher ordinals.
+var table = []int64{2}
+
+// Get returns the i'th prime (0-indexed, so Get(0) == 2).
+func Get(i int) int64 {
+	for len(table) <= i {
+		table = append(table, nextPrime(table[len(table)-1]))
+	}
+	return table[i]
+}
+
+// nextPrime returns the smallest prime strictly greater than p.
+func nextPrime(p int64) int64 {
+	for n := p + 1; ; n++ {
+		if isPrimeTrialDivision(n) {
+			return n
+		}
+	}
+}
+
+// isPrimeTrialDivision reports whether n is prime by trial division. It is
+// only fast enough for the small primes used to seed table.
+func isPrimeTrialDivision(n int64) bool {
+	if n < 2 {
+		return false
+	}
+	for d := int64(2); d*d <= n; d++ {
+		if n%d == 0 {
+			return false
+		}
+	}
+	return true
+}
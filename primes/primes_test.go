@@ -0,0 +1,69 @@
+package primes
+
+import "testing"
+
+func TestRange(t *testing.T) {
+	const hi = 10000
+
+	var want []int64
+	for n := int64(2); n < hi; n++ {
+		if isPrimeTrialDivision(n) {
+			want = append(want, n)
+		}
+	}
+
+	for _, lo := range []int64{0, 1, 2, 97, 1000, 9999} {
+		var wantRange []int64
+		for _, p := range want {
+			if p >= lo {
+				wantRange = append(wantRange, p)
+			}
+		}
+
+		got := Range(lo, hi)
+		if len(got) != len(wantRange) {
+			t.Fatalf("Range(%d,%d): got %d primes, want %d", lo, hi, len(got), len(wantRange))
+		}
+		for i := range got {
+			if got[i] != wantRange[i] {
+				t.Errorf("Range(%d,%d)[%d]=%d, want %d", lo, hi, i, got[i], wantRange[i])
+			}
+		}
+	}
+}
+
+func TestRangeSpanningSegments(t *testing.T) {
+	// Exercise a range wide enough to cross several segment boundaries.
+	lo, hi := int64(1), int64(5*segmentSize+17)
+
+	got := Range(lo, hi)
+	gotSet := make(map[int64]bool, len(got))
+	for _, p := range got {
+		if !isPrimeTrialDivision(p) {
+			t.Errorf("Range(%d,%d) returned composite %d", lo, hi, p)
+		}
+		gotSet[p] = true
+	}
+
+	for n := lo; n < hi; n++ {
+		if isPrimeTrialDivision(n) != gotSet[n] {
+			t.Errorf("Range(%d,%d) membership wrong for %d: got %t, want %t", lo, hi, n, gotSet[n], isPrimeTrialDivision(n))
+		}
+	}
+}
+
+func BenchmarkGetFirst10000(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		table = []int64{2}
+		for j := 0; j < 10000; j++ {
+			Get(j)
+		}
+	}
+}
+
+func BenchmarkRangeFirst10000(b *testing.B) {
+	hi := Get(9999) + 1
+	for i := 0; i < b.N; i++ {
+		Range(0, hi)
+	}
+}
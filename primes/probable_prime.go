@@ -0,0 +1,188 @@
+package primes
+
+import (
+	"github.com/cfschilham/factorlib/big"
+)
+
+// smallPrimeBound is how far trial division runs before falling back to
+// Miller-Rabin and the Lucas test - cheap enough to weed out almost all
+// composites before paying for modular exponentiation.
+const smallPrimeBound = 1000
+
+// IsProbablePrime reports whether n is prime, using the Baillie-PSW test:
+// trial division by small primes, a Miller-Rabin test to base 2, and a
+// strong Lucas probable-prime test with Selfridge's parameter selection.
+// No composite n is known to pass all three, so a true result can be
+// trusted as definitive in practice, even though the test is not proven
+// to have zero false positives for arbitrarily large n.
+func IsProbablePrime(n big.Int) bool {
+	zero, two := big.Int64(0), big.Int64(2)
+
+	if n.Cmp(two) < 0 {
+		return false
+	}
+	if n.Cmp(two) == 0 {
+		return true
+	}
+	if n.Mod(two).Cmp(zero) == 0 {
+		return false
+	}
+
+	for _, p := range smallPrimesUpTo(smallPrimeBound) {
+		bp := big.Int64(p)
+		if n.Cmp(bp) == 0 {
+			return true
+		}
+		if n.Mod(bp).Sign() == 0 {
+			return false
+		}
+	}
+
+	if !millerRabinBase2(n) {
+		return false
+	}
+
+	if isPerfectSquare(n) {
+		// A strong Lucas test with a Selfridge D can never terminate its
+		// search against a perfect square, since (D/n) is never -1.
+		return false
+	}
+
+	return strongLucasPRP(n)
+}
+
+// IsPrime reports whether n is prime. Baillie-PSW (what IsProbablePrime
+// implements) has no known counterexample and is proven deterministic for
+// all n < 2^64, which every int64 satisfies, so the result here is
+// definitive rather than merely probabilistic.
+func IsPrime(n int64) bool {
+	return IsProbablePrime(big.Int64(n))
+}
+
+// millerRabinBase2 reports whether n passes a single strong
+// probable-prime test to base 2. n must be odd and >= 3.
+func millerRabinBase2(n big.Int) bool {
+	one := big.Int64(1)
+	two := big.Int64(2)
+	nMinus1 := n.Sub(one)
+
+	d, r := nMinus1, uint(0)
+	for d.IsEven() {
+		d = d.Div(two)
+		r++
+	}
+
+	x := two.Exp(d, n)
+	if x.Cmp(one) == 0 || x.Cmp(nMinus1) == 0 {
+		return true
+	}
+	for i := uint(1); i < r; i++ {
+		x = x.Mul(x).Mod(n)
+		if x.Cmp(nMinus1) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// bigJacobi returns the Jacobi symbol (a/n) for odd n. It mirrors
+// math.BigJacobi; it is duplicated rather than imported because primes
+// sits below math in the dependency graph (math's tests use primes to
+// generate test primes, so the reverse import would cycle).
+func bigJacobi(a, n big.Int) int {
+	two, one, four, eight := big.Int64(2), big.Int64(1), big.Int64(4), big.Int64(8)
+
+	a = a.Mod(n)
+	result := 1
+	for a.Sign() != 0 {
+		for a.Mod(two).Sign() == 0 {
+			a = a.Div(two)
+			if r := n.Mod(eight).Int64(); r == 3 || r == 5 {
+				result = -result
+			}
+		}
+		a, n = n, a
+		if a.Mod(four).Int64() == 3 && n.Mod(four).Int64() == 3 {
+			result = -result
+		}
+		a = a.Mod(n)
+	}
+	if n.Cmp(one) == 0 {
+		return result
+	}
+	return 0
+}
+
+// isPerfectSquare reports whether n is a perfect square.
+func isPerfectSquare(n big.Int) bool {
+	root := big.Sqrt(n)
+	return root.Square().Cmp(n) == 0
+}
+
+// selfridgeD returns the first D in the sequence 5, -7, 9, -11, 13, ...
+// with Jacobi(D, n) == -1, along with the matching Lucas parameters
+// P=1, Q=(1-D)/4, per Selfridge's method A.
+func selfridgeD(n big.Int) (d, p, q int64) {
+	mag, sign := int64(5), int64(1)
+	for {
+		d = sign * mag
+		if bigJacobi(big.Int64(d), n) == -1 {
+			return d, 1, (1 - d) / 4
+		}
+		mag += 2
+		sign = -sign
+	}
+}
+
+// strongLucasPRP reports whether n passes a strong Lucas probable-prime
+// test with Selfridge's parameters. n must be odd, >= 3, and not a
+// perfect square.
+func strongLucasPRP(n big.Int) bool {
+	d, _, q := selfridgeD(n)
+
+	one, two := big.Int64(1), big.Int64(2)
+	bigD := big.Int64(d)
+	bigQ := big.Int64(q)
+	inv2 := two.ModInverse(n)
+
+	nPlus1 := n.Add(one)
+	delta, s := nPlus1, uint(0)
+	for delta.IsEven() {
+		delta = delta.Div(two)
+		s++
+	}
+
+	// Compute (U_delta, V_delta, Q^delta) mod n via the standard
+	// doubling/addition recurrences for P=1, walking delta's bits from
+	// the second-most-significant down.
+	u, v, qk := one, one, bigQ.Mod(n)
+	for i := delta.BitLen() - 2; i >= 0; i-- {
+		// Double: index k -> 2k.
+		u2 := u.Mul(v).Mod(n)
+		v2 := v.Mul(v).Sub(qk.Mul(two)).Mod(n)
+		qk = qk.Mul(qk).Mod(n)
+
+		if delta.Bit(i) == 1 {
+			// Add one: index 2k -> 2k+1 (P=1).
+			u = u2.Add(v2).Mul(inv2).Mod(n)
+			v = bigD.Mul(u2).Add(v2).Mul(inv2).Mod(n)
+			qk = qk.Mul(bigQ).Mod(n)
+		} else {
+			u, v = u2, v2
+		}
+	}
+
+	if u.Sign() == 0 {
+		return true
+	}
+	for r := uint(0); r < s; r++ {
+		if v.Sign() == 0 {
+			return true
+		}
+		if r < s-1 {
+			v = v.Mul(v).Sub(qk.Mul(two)).Mod(n)
+			qk = qk.Mul(qk).Mod(n)
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package primes
+
+import (
+	"testing"
+
+	"github.com/cfschilham/factorlib/big"
+)
+
+func TestIsPrime(t *testing.T) {
+	const n = 20000
+	want := make([]bool, n)
+	for i := int64(2); i < n; i++ {
+		want[i] = isPrimeTrialDivision(i)
+	}
+
+	for i := int64(0); i < n; i++ {
+		if got := IsPrime(i); got != want[i] {
+			t.Errorf("IsPrime(%d)=%t, want %t", i, got, want[i])
+		}
+	}
+}
+
+func TestIsProbablePrimeKnownPrimes(t *testing.T) {
+	// Larger primes, beyond what trial division alone would catch
+	// quickly, to exercise Miller-Rabin and the Lucas test.
+	known := []int64{
+		999999937,
+		1000000007,
+		1000000009,
+		2147483647, // 2^31 - 1, a Mersenne prime
+	}
+	for _, p := range known {
+		if !IsProbablePrime(big.Int64(p)) {
+			t.Errorf("IsProbablePrime(%d) = false, want true", p)
+		}
+	}
+}
+
+func TestIsProbablePrimeKnownComposites(t *testing.T) {
+	// Carmichael numbers and squares of primes are classic traps for
+	// incomplete primality tests.
+	composites := []int64{
+		561, // smallest Carmichael number
+		1105,
+		1729,
+		41 * 41,
+		997 * 997,
+	}
+	for _, c := range composites {
+		if IsProbablePrime(big.Int64(c)) {
+			t.Errorf("IsProbablePrime(%d) = true, want false", c)
+		}
+	}
+}
+
+func TestIsProbablePrimePerfectSquare(t *testing.T) {
+	for _, p := range []int64{7, 97, 10007} {
+		sq := p * p
+		if IsProbablePrime(big.Int64(sq)) {
+			t.Errorf("IsProbablePrime(%d) = true, want false", sq)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package primes
+
+import "math"
+
+// segmentSize is the width of each sieved window, chosen to fit comfortably
+// in L1 cache regardless of how large [lo, hi) is.
+const segmentSize = 32 * 1024
+
+// Sieve calls visit, in increasing order, for every prime p in [lo, hi).
+// Unlike Get, which walks a table from the start, Sieve enumerates an
+// arbitrary range using a segmented sieve of Eratosthenes: it never
+// allocates more than O(sqrt(hi) + segmentSize) memory, so it stays
+// practical for factor bases well beyond what a table indexed from zero
+// could reach (such as those for numbers up to roughly 2^100). lo and hi
+// are int64, so the sieved range itself stays below 2^63.
+func Sieve(lo, hi int64, visit func(p int64)) {
+	if lo < 2 {
+		lo = 2
+	}
+	if hi <= lo {
+		return
+	}
+
+	small := smallPrimesUpTo(isqrt(hi - 1))
+
+	for segLo := lo; segLo < hi; segLo += segmentSize {
+		segHi := segLo + segmentSize
+		if segHi > hi {
+			segHi = hi
+		}
+		composite := make([]bool, segHi-segLo)
+
+		for _, p := range small {
+			if p*p >= segHi {
+				break
+			}
+			start := p * p
+			if start < segLo {
+				start = ((segLo + p - 1) / p) * p
+			}
+			for m := start; m < segHi; m += p {
+				composite[m-segLo] = true
+			}
+		}
+
+		for i, isComposite := range composite {
+			if !isComposite {
+				visit(segLo + int64(i))
+			}
+		}
+	}
+}
+
+// Range returns, as a slice, every prime in [lo, hi).
+func Range(lo, hi int64) []int64 {
+	var ps []int64
+	Sieve(lo, hi, func(p int64) {
+		ps = append(ps, p)
+	})
+	return ps
+}
+
+// smallPrimesUpTo returns every prime <= limit via a plain sieve of
+// Eratosthenes, used to seed the segmented sieve above.
+func smallPrimesUpTo(limit int64) []int64 {
+	if limit < 2 {
+		return nil
+	}
+	composite := make([]bool, limit+1)
+	var ps []int64
+	for p := int64(2); p <= limit; p++ {
+		if composite[p] {
+			continue
+		}
+		ps = append(ps, p)
+		for m := p * p; m <= limit; m += p {
+			composite[m] = true
+		}
+	}
+	return ps
+}
+
+// isqrt returns floor(sqrt(n)) for n >= 0.
+func isqrt(n int64) int64 {
+	if n < 0 {
+		return 0
+	}
+	if n == 0 {
+		return 0
+	}
+	r := int64(math.Sqrt(float64(n)))
+	for r*r > n {
+		r--
+	}
+	for (r+1)*(r+1) <= n {
+		r++
+	}
+	return r
+}